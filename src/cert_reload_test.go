@@ -0,0 +1,322 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert generates a throwaway self-signed cert/key pair with
+// the given common name and writes it to certPath/keyPath in PEM form.
+func writeSelfSignedCert(t *testing.T, certPath, keyPath, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+
+	derCert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+
+	derKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certOut, err := os.Create(certPath)
+	if err != nil {
+		t.Fatalf("create cert file: %v", err)
+	}
+	defer certOut.Close()
+	if err := pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: derCert}); err != nil {
+		t.Fatalf("encode cert: %v", err)
+	}
+
+	keyOut, err := os.Create(keyPath)
+	if err != nil {
+		t.Fatalf("create key file: %v", err)
+	}
+	defer keyOut.Close()
+	if err := pem.Encode(keyOut, &pem.Block{Type: "EC PRIVATE KEY", Bytes: derKey}); err != nil {
+		t.Fatalf("encode key: %v", err)
+	}
+}
+
+func TestCertReloaderReload(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	writeSelfSignedCert(t, certPath, keyPath, "first-leaf")
+
+	reloader, err := newCertReloader(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate: %v", err)
+	}
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	if parsed.Subject.CommonName != "first-leaf" {
+		t.Fatalf("expected first-leaf, got %s", parsed.Subject.CommonName)
+	}
+
+	writeSelfSignedCert(t, certPath, keyPath, "second-leaf")
+
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	cert, err = reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate after reload: %v", err)
+	}
+
+	parsed, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate after reload: %v", err)
+	}
+	if parsed.Subject.CommonName != "second-leaf" {
+		t.Fatalf("expected second-leaf after reload, got %s", parsed.Subject.CommonName)
+	}
+}
+
+// TestCertReloaderServesOverRealHandshake wires a reloader's GetCertificate
+// into an actual tls.Listener so the handshake path exercised by
+// configureTLS (not just GetCertificate in isolation) is covered, including
+// a reload taking effect on the next handshake without restarting the
+// listener.
+func TestCertReloaderServesOverRealHandshake(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	writeSelfSignedCert(t, certPath, keyPath, "first-leaf")
+
+	reloader, err := newCertReloader(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{GetCertificate: reloader.GetCertificate})
+	if err != nil {
+		t.Fatalf("tls.Listen: %v", err)
+	}
+	defer listener.Close()
+
+	go acceptAndClose(listener)
+
+	if got := dialAndPeerCommonName(t, listener.Addr().String()); got != "first-leaf" {
+		t.Fatalf("peer CommonName = %q, want first-leaf", got)
+	}
+
+	writeSelfSignedCert(t, certPath, keyPath, "second-leaf")
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	go acceptAndClose(listener)
+
+	if got := dialAndPeerCommonName(t, listener.Addr().String()); got != "second-leaf" {
+		t.Fatalf("peer CommonName after reload = %q, want second-leaf", got)
+	}
+}
+
+// TestConfigureTLSRequiresClientCertificate exercises the mTLS path wired
+// by configureTLS: a client presenting a certificate signed by the
+// configured CA is accepted, and a client presenting none is rejected.
+func TestConfigureTLSRequiresClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	caCertPath := filepath.Join(dir, "ca.crt")
+
+	writeSelfSignedCert(t, certPath, keyPath, "server-leaf")
+	caCert, caKey := writeSelfSignedCA(t, caCertPath, "test-ca")
+	clientCert := signedClientCert(t, "test-client", caCert, caKey)
+
+	t.Setenv("TLS_CERT_FILE", certPath)
+	t.Setenv("TLS_KEY_FILE", keyPath)
+	t.Setenv("TLS_CLIENT_CA_FILE", caCertPath)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})}
+
+	if _, err := configureTLS(srv); err != nil {
+		t.Fatalf("configureTLS: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		_ = srv.ServeTLS(listener, "", "")
+	}()
+	defer srv.Close()
+
+	addr := listener.Addr().String()
+
+	withCert := &tls.Config{InsecureSkipVerify: true, Certificates: []tls.Certificate{clientCert}}
+	conn, err := tls.Dial("tcp", addr, withCert)
+	if err != nil {
+		t.Fatalf("dial with client certificate: %v", err)
+	}
+	conn.Close()
+
+	withoutCert := &tls.Config{InsecureSkipVerify: true}
+	rejected, err := tls.Dial("tcp", addr, withoutCert)
+	if err == nil {
+		// Under TLS 1.3 the client finishes its side of the handshake before
+		// the server checks for a certificate, so the server's rejection
+		// surfaces on the first read rather than from Dial.
+		defer rejected.Close()
+		_, err = rejected.Read(make([]byte, 1))
+	}
+	if err == nil {
+		t.Fatal("connect without client certificate: expected rejection, got nil error")
+	}
+}
+
+// acceptAndClose accepts a single connection, completing its TLS handshake
+// as a side effect, then closes it.
+func acceptAndClose(listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		_ = tlsConn.Handshake()
+	}
+	conn.Close()
+}
+
+// dialAndPeerCommonName dials addr over TLS and returns the CommonName of
+// the leaf certificate the server presented.
+func dialAndPeerCommonName(t *testing.T, addr string) string {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("tls.Dial: %v", err)
+	}
+	defer conn.Close()
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		t.Fatal("no peer certificates presented")
+	}
+	return state.PeerCertificates[0].Subject.CommonName
+}
+
+// writeSelfSignedCA generates a throwaway self-signed CA key/cert pair,
+// writes the cert to certPath in PEM form, and returns both for signing
+// client certificates.
+func writeSelfSignedCA(t *testing.T, certPath, commonName string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA certificate: %v", err)
+	}
+
+	certOut, err := os.Create(certPath)
+	if err != nil {
+		t.Fatalf("create CA cert file: %v", err)
+	}
+	defer certOut.Close()
+	if err := pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatalf("encode CA cert: %v", err)
+	}
+
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse CA certificate: %v", err)
+	}
+	return ca, key
+}
+
+// signedClientCert generates a client key/cert pair signed by ca/caKey,
+// ready to present in a tls.Config.Certificates slice.
+func signedClientCert(t *testing.T, commonName string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate client key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create client certificate: %v", err)
+	}
+
+	derKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal client key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derKey})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair: %v", err)
+	}
+	return cert
+}
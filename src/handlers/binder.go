@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hw5/models"
+)
+
+// decode reads the request body into dst, choosing a decoding strategy
+// from the Content-Type header. It supports application/json (default
+// when no recognized type is present, to stay lenient with older
+// clients), application/xml and text/xml, and
+// application/x-www-form-urlencoded. dst must be a *models.Product.
+func decode(r *http.Request, dst *models.Product) error {
+	contentType := contentTypeOf(r.Header.Get("Content-Type"))
+
+	switch {
+	case strings.HasPrefix(contentType, "application/xml"), strings.HasPrefix(contentType, "text/xml"):
+		return xml.NewDecoder(r.Body).Decode(dst)
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		return decodeForm(r, dst)
+	default:
+		return json.NewDecoder(r.Body).Decode(dst)
+	}
+}
+
+// decodeForm parses the request's form body and populates dst's fields
+// using their `form` struct tags.
+func decodeForm(r *http.Request, dst *models.Product) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(dst).Elem()
+	typ := value.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("form")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		raw := r.PostForm.Get(tag)
+		if raw == "" {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		switch fieldValue.Kind() {
+		case reflect.String:
+			fieldValue.SetString(raw)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("field %q: %w", tag, err)
+			}
+			fieldValue.SetInt(n)
+		default:
+			return fmt.Errorf("field %q: unsupported form field type %s", tag, fieldValue.Kind())
+		}
+	}
+
+	return nil
+}
+
+// contentTypeOf strips any parameters (e.g. "; charset=utf-8") from a
+// Content-Type header value.
+func contentTypeOf(header string) string {
+	if idx := strings.IndexByte(header, ';'); idx != -1 {
+		header = header[:idx]
+	}
+	return strings.TrimSpace(header)
+}
+
+// render writes data to w in the format requested by the Accept header,
+// falling back to JSON when the header is absent or no supported type is
+// accepted. Unlike decode, render does not offer
+// application/x-www-form-urlencoded as a response encoding: form encoding
+// has no natural representation for a nested or repeated field (e.g. the
+// product list in ProductListResponse), so form support is intentionally
+// request-body-only. An Accept: application/x-www-form-urlencoded request
+// falls back to JSON, same as any other unsupported media type.
+func render(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	switch acceptedType(r.Header.Get("Accept")) {
+	case "application/xml", "text/xml":
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(status)
+		_ = xml.NewEncoder(w).Encode(data)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(data)
+	}
+}
+
+// acceptQuality is one media range parsed out of an Accept header, along
+// with its relative "q" weight.
+type acceptQuality struct {
+	mediaType string
+	q         float64
+}
+
+// acceptedType picks the highest-priority supported media type from an
+// Accept header's comma-separated list of media ranges, each optionally
+// carrying a "q" parameter (e.g. "application/xml;q=0.9"). It recognizes
+// application/json, application/xml and text/xml; anything else (including
+// "*/*" and application/x-www-form-urlencoded, which render does not
+// produce) resolves to application/json.
+func acceptedType(header string) string {
+	if header == "" {
+		return "application/json"
+	}
+
+	supported := map[string]bool{
+		"application/json": true,
+		"application/xml":  true,
+		"text/xml":         true,
+	}
+
+	var ranges []acceptQuality
+	for _, part := range strings.Split(header, ",") {
+		mediaType, q := parseMediaRange(part)
+		if mediaType == "*/*" || mediaType == "application/*" {
+			mediaType = "application/json"
+		}
+		if !supported[mediaType] {
+			continue
+		}
+		ranges = append(ranges, acceptQuality{mediaType: mediaType, q: q})
+	}
+
+	if len(ranges) == 0 {
+		return "application/json"
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	return ranges[0].mediaType
+}
+
+// parseMediaRange splits a single Accept media range (e.g.
+// "application/xml;q=0.8") into its media type and q value, defaulting to
+// q=1.0 when absent or malformed.
+func parseMediaRange(part string) (string, float64) {
+	segments := strings.Split(part, ";")
+	mediaType := strings.TrimSpace(segments[0])
+
+	q := 1.0
+	for _, param := range segments[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+			q = parsed
+		}
+	}
+
+	return mediaType, q
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"hw5/models"
+)
+
+func TestDecodeXML(t *testing.T) {
+	body := `<product><product_id>7</product_id><sku>SKU-1</sku><manufacturer>Acme</manufacturer>` +
+		`<category_id>2</category_id><weight>3</weight><some_other_id>4</some_other_id></product>`
+
+	req := httptest.NewRequest(http.MethodPost, "/products/7/details", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/xml")
+
+	var product models.Product
+	if err := decode(req, &product); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	want := models.Product{ProductID: 7, SKU: "SKU-1", Manufacturer: "Acme", CategoryID: 2, Weight: 3, SomeOtherID: 4}
+	if product.ProductID != want.ProductID || product.SKU != want.SKU || product.Manufacturer != want.Manufacturer ||
+		product.CategoryID != want.CategoryID || product.Weight != want.Weight || product.SomeOtherID != want.SomeOtherID {
+		t.Fatalf("decoded product = %+v, want fields %+v", product, want)
+	}
+}
+
+func TestDecodeForm(t *testing.T) {
+	form := url.Values{
+		"product_id":    {"7"},
+		"sku":           {"SKU-1"},
+		"manufacturer":  {"Acme"},
+		"category_id":   {"2"},
+		"weight":        {"3"},
+		"some_other_id": {"4"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/products/7/details", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var product models.Product
+	if err := decode(req, &product); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	want := models.Product{ProductID: 7, SKU: "SKU-1", Manufacturer: "Acme", CategoryID: 2, Weight: 3, SomeOtherID: 4}
+	if product.ProductID != want.ProductID || product.SKU != want.SKU || product.Manufacturer != want.Manufacturer ||
+		product.CategoryID != want.CategoryID || product.Weight != want.Weight || product.SomeOtherID != want.SomeOtherID {
+		t.Fatalf("decoded product = %+v, want fields %+v", product, want)
+	}
+}
+
+func TestDecodeFormInvalidInt(t *testing.T) {
+	form := url.Values{"product_id": {"not-a-number"}, "sku": {"x"}, "manufacturer": {"x"}}
+
+	req := httptest.NewRequest(http.MethodPost, "/products/1/details", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var product models.Product
+	if err := decode(req, &product); err == nil {
+		t.Fatal("decode: expected error for non-numeric product_id, got nil")
+	}
+}
+
+func TestRenderNegotiatesXML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set("Accept", "application/xml")
+
+	render(rec, req, http.StatusOK, models.Product{ProductID: 7, SKU: "SKU-1"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/xml" {
+		t.Fatalf("Content-Type = %q, want application/xml", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "<sku>SKU-1</sku>") {
+		t.Fatalf("body = %q, want it to contain the SKU element", rec.Body.String())
+	}
+}
+
+func TestRenderFallsBackToJSONForFormAccept(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set("Accept", "application/x-www-form-urlencoded")
+
+	render(rec, req, http.StatusOK, models.Product{ProductID: 7, SKU: "SKU-1"})
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `"sku":"SKU-1"`) {
+		t.Fatalf("body = %q, want it to contain the JSON sku field", rec.Body.String())
+	}
+}
+
+func TestAcceptedTypeHonorsQValues(t *testing.T) {
+	got := acceptedType("application/json;q=0.5, application/xml;q=0.9")
+	if got != "application/xml" {
+		t.Fatalf("acceptedType = %q, want application/xml", got)
+	}
+}
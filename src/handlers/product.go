@@ -1,9 +1,12 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
 
@@ -15,69 +18,167 @@ import (
 
 // ErrorResponse models the error schema defined in the OpenAPI specification.
 type ErrorResponse struct {
-	Error   string  `json:"error"`
-	Message string  `json:"message"`
-	Details *string `json:"details,omitempty"`
+	XMLName xml.Name `json:"-" xml:"error"`
+	Error   string   `json:"error" xml:"code"`
+	Message string   `json:"message" xml:"message"`
+	Details *string  `json:"details,omitempty" xml:"details,omitempty"`
 }
 
 // Handler exposes HTTP handlers for product operations.
 type Handler struct {
-	store *storage.MemoryStore
+	store storage.Store
 }
 
 // NewHandler creates a Handler backed by the provided store.
-func NewHandler(store *storage.MemoryStore) *Handler {
+func NewHandler(store storage.Store) *Handler {
 	return &Handler{store: store}
 }
 
 // RegisterRoutes wires product routes onto the provided router.
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/products", func(router chi.Router) {
+		router.Get("/", h.handleListProducts)
 		router.Get("/{productId}", h.handleGetProduct)
 		router.Post("/{productId}/details", h.handleUpsertProduct)
 	})
 }
 
+const (
+	defaultPage  = 1
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// ProductListResponse is the paginated response returned by GET /products.
+type ProductListResponse struct {
+	XMLName  xml.Name         `json:"-" xml:"products"`
+	Items    []models.Product `json:"items" xml:"items>product"`
+	Page     int              `json:"page" xml:"page"`
+	Limit    int              `json:"limit" xml:"limit"`
+	Total    int              `json:"total" xml:"total"`
+	NextPage *int             `json:"next_page" xml:"next_page,omitempty"`
+}
+
+func (h *Handler) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := parsePagination(r)
+	if err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		return
+	}
+
+	filter := storage.Filter{
+		Manufacturer: r.URL.Query().Get("manufacturer"),
+		SKUPrefix:    r.URL.Query().Get("sku_prefix"),
+	}
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		categoryID, err := strconv.Atoi(raw)
+		if err != nil || categoryID < 1 {
+			h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", "category_id must be a positive integer")
+			return
+		}
+		filter.CategoryID = categoryID
+	}
+
+	items, total, err := h.store.ListProducts(r.Context(), filter, page, limit)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			h.writeError(w, r, http.StatusGatewayTimeout, "REQUEST_TIMEOUT", "Listing products took too long")
+			return
+		}
+		log.Printf("ERROR: failed to list products: %v", err)
+		h.writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list products")
+		return
+	}
+
+	response := ProductListResponse{
+		Items: items,
+		Page:  page,
+		Limit: limit,
+		Total: total,
+	}
+	if page*limit < total {
+		next := page + 1
+		response.NextPage = &next
+	}
+
+	render(w, r, http.StatusOK, response)
+}
+
+func parsePagination(r *http.Request) (page, limit int, err error) {
+	page = defaultPage
+	if raw := r.URL.Query().Get("page"); raw != "" {
+		page, err = strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return 0, 0, errors.New("page must be a positive integer")
+		}
+	}
+
+	limit = defaultLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		if limit > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be %d or fewer", maxLimit)
+		}
+	}
+
+	// (page-1)*limit is the offset the stores compute internally; reject
+	// any page that would overflow it rather than let it wrap negative and
+	// panic on a slice expression downstream (storage.MemoryStore and
+	// storage.BoltStore both index with this offset).
+	if page-1 > math.MaxInt/limit {
+		return 0, 0, fmt.Errorf("page is too large for limit %d", limit)
+	}
+
+	return page, limit, nil
+}
+
 func (h *Handler) handleGetProduct(w http.ResponseWriter, r *http.Request) {
 	productID, err := parseProductID(r)
 	if err != nil {
-		h.writeError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", err.Error())
 		return
 	}
 
-	product, err := h.store.GetProduct(productID)
+	product, err := h.store.GetProduct(r.Context(), productID)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
-			h.writeError(w, http.StatusNotFound, "PRODUCT_NOT_FOUND", "The requested product does not exist")
+			h.writeError(w, r, http.StatusNotFound, "PRODUCT_NOT_FOUND", "The requested product does not exist")
 			return
 		}
 		log.Printf("ERROR: failed to retrieve product %d: %v", productID, err)
-		h.writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve product")
+		h.writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to retrieve product")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, product)
+	render(w, r, http.StatusOK, product)
 }
 
 func (h *Handler) handleUpsertProduct(w http.ResponseWriter, r *http.Request) {
 	productID, err := parseProductID(r)
 	if err != nil {
-		h.writeError(w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", err.Error())
 		return
 	}
 
 	var payload models.Product
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		h.writeError(w, http.StatusBadRequest, "INVALID_INPUT", "Invalid JSON payload")
+	if err := decode(r, &payload); err != nil {
+		h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", "Invalid request payload")
 		return
 	}
 
 	if validationErr := validateProductPayload(productID, payload); validationErr != nil {
-		h.writeError(w, http.StatusBadRequest, "INVALID_INPUT", validationErr.Error())
+		h.writeError(w, r, http.StatusBadRequest, "INVALID_INPUT", validationErr.Error())
 		return
 	}
 
-	h.store.UpsertProduct(payload)
+	if err := h.store.UpsertProduct(r.Context(), payload); err != nil {
+		log.Printf("ERROR: failed to upsert product %d: %v", productID, err)
+		h.writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to save product")
+		return
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -130,12 +231,6 @@ func validateProductPayload(expectedID int, product models.Product) error {
 	return nil
 }
 
-func (h *Handler) writeError(w http.ResponseWriter, status int, code, message string) {
-	writeJSON(w, status, ErrorResponse{Error: code, Message: message})
-}
-
-func writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(data)
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	render(w, r, status, ErrorResponse{Error: code, Message: message})
 }
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hw5/models"
+	"hw5/storage"
+)
+
+func newTestHandler(t *testing.T, products ...models.Product) *Handler {
+	t.Helper()
+
+	store := storage.NewMemoryStore()
+	for _, product := range products {
+		if err := store.UpsertProduct(context.Background(), product); err != nil {
+			t.Fatalf("UpsertProduct(%d): %v", product.ProductID, err)
+		}
+	}
+	return NewHandler(store)
+}
+
+func listProducts(t *testing.T, h *Handler, query string) ProductListResponse {
+	t.Helper()
+
+	router := chi.NewRouter()
+	h.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/products"+query, nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body = %s", rec.Code, rec.Body.String())
+	}
+
+	var response ProductListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	return response
+}
+
+func seededProducts(n int) []models.Product {
+	products := make([]models.Product, n)
+	for i := range products {
+		products[i] = models.Product{ProductID: i + 1, SKU: "sku", Manufacturer: "m", CategoryID: 1, SomeOtherID: 1}
+	}
+	return products
+}
+
+func TestHandleListProductsDefaultPagination(t *testing.T) {
+	h := newTestHandler(t, seededProducts(3)...)
+
+	response := listProducts(t, h, "")
+
+	if response.Page != 1 || response.Limit != defaultLimit {
+		t.Fatalf("page/limit = %d/%d, want 1/%d", response.Page, response.Limit, defaultLimit)
+	}
+	if response.Total != 3 || len(response.Items) != 3 {
+		t.Fatalf("total/items = %d/%d, want 3/3", response.Total, len(response.Items))
+	}
+	if response.NextPage != nil {
+		t.Fatalf("next_page = %v, want nil", *response.NextPage)
+	}
+}
+
+func TestHandleListProductsPaginationBoundaries(t *testing.T) {
+	h := newTestHandler(t, seededProducts(5)...)
+
+	response := listProducts(t, h, "?page=1&limit=2")
+	if len(response.Items) != 2 || response.Items[0].ProductID != 1 || response.Items[1].ProductID != 2 {
+		t.Fatalf("page 1 items = %+v, want [1 2]", response.Items)
+	}
+	if response.NextPage == nil || *response.NextPage != 2 {
+		t.Fatalf("next_page = %v, want 2", response.NextPage)
+	}
+
+	response = listProducts(t, h, "?page=3&limit=2")
+	if len(response.Items) != 1 || response.Items[0].ProductID != 5 {
+		t.Fatalf("last partial page items = %+v, want [5]", response.Items)
+	}
+	if response.NextPage != nil {
+		t.Fatalf("next_page on last page = %v, want nil", *response.NextPage)
+	}
+
+	response = listProducts(t, h, "?page=4&limit=2")
+	if len(response.Items) != 0 {
+		t.Fatalf("page past the end items = %+v, want []", response.Items)
+	}
+	if response.Total != 5 {
+		t.Fatalf("total on out-of-range page = %d, want 5", response.Total)
+	}
+}
+
+func TestHandleListProductsRejectsLimitAboveMax(t *testing.T) {
+	h := newTestHandler(t, seededProducts(1)...)
+
+	router := chi.NewRouter()
+	h.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/products?limit=201", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400; body = %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleListProductsRejectsOverflowingPage(t *testing.T) {
+	h := newTestHandler(t, seededProducts(1)...)
+
+	router := chi.NewRouter()
+	h.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/products?page=100000000000000000&limit=200", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400; body = %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleListProductsFiltersByManufacturer(t *testing.T) {
+	h := newTestHandler(t,
+		models.Product{ProductID: 1, SKU: "a", Manufacturer: "Acme", CategoryID: 1, SomeOtherID: 1},
+		models.Product{ProductID: 2, SKU: "b", Manufacturer: "Globex", CategoryID: 1, SomeOtherID: 1},
+	)
+
+	response := listProducts(t, h, "?manufacturer=Acme")
+
+	if response.Total != 1 || len(response.Items) != 1 || response.Items[0].ProductID != 1 {
+		t.Fatalf("filtered items = %+v, total = %d, want just product 1", response.Items, response.Total)
+	}
+}
@@ -0,0 +1,258 @@
+// Package compress provides an HTTP middleware that transparently
+// compresses response bodies with gzip or deflate when the client
+// supports it and the response is large enough and of an eligible
+// content type to be worth compressing.
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultThreshold is the minimum response body size, in bytes, before
+// compression is attempted.
+const DefaultThreshold = 1024
+
+// DefaultContentTypes are the Content-Type prefixes eligible for
+// compression when no allow-list is configured.
+var DefaultContentTypes = []string{"application/json", "application/xml", "text/"}
+
+// Options configures the compression middleware.
+type Options struct {
+	// Threshold is the minimum response body size, in bytes, before
+	// compression is attempted. Responses smaller than this are written
+	// through unmodified.
+	Threshold int
+
+	// Level is the compression level passed to gzip/flate: either
+	// gzip.DefaultCompression or a value in the range
+	// gzip.BestSpeed..gzip.BestCompression.
+	Level int
+
+	// ContentTypes is the set of Content-Type prefixes eligible for
+	// compression (e.g. "application/json", "text/").
+	ContentTypes []string
+}
+
+// OptionsFromEnv builds Options from COMPRESSION_THRESHOLD_BYTES,
+// COMPRESSION_LEVEL and COMPRESSION_CONTENT_TYPES (comma-separated),
+// falling back to DefaultThreshold, gzip.DefaultCompression and
+// DefaultContentTypes respectively when unset or invalid.
+func OptionsFromEnv() Options {
+	opts := Options{
+		Threshold:    DefaultThreshold,
+		Level:        gzip.DefaultCompression,
+		ContentTypes: DefaultContentTypes,
+	}
+
+	if raw := os.Getenv("COMPRESSION_THRESHOLD_BYTES"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			opts.Threshold = n
+		}
+	}
+
+	if raw := os.Getenv("COMPRESSION_LEVEL"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= gzip.BestSpeed && n <= gzip.BestCompression {
+			opts.Level = n
+		}
+	}
+
+	if raw := os.Getenv("COMPRESSION_CONTENT_TYPES"); raw != "" {
+		types := strings.Split(raw, ",")
+		for i, t := range types {
+			types[i] = strings.TrimSpace(t)
+		}
+		opts.ContentTypes = types
+	}
+
+	return opts
+}
+
+// Middleware returns a middleware that compresses eligible responses
+// according to opts.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buf := &bufferingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(buf, r)
+
+			w.Header().Set("Vary", "Accept-Encoding")
+
+			if !buf.eligible(opts) {
+				buf.flushRaw()
+				return
+			}
+
+			flushCompressed(w, buf, encoding, opts.Level)
+		})
+	}
+}
+
+// bufferingWriter captures the handler's response so the middleware can
+// inspect its size and Content-Type before deciding whether to compress.
+type bufferingWriter struct {
+	http.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (b *bufferingWriter) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+}
+
+func (b *bufferingWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferingWriter) eligible(opts Options) bool {
+	if b.status == http.StatusNoContent || b.body.Len() == 0 {
+		return false
+	}
+	if b.body.Len() < opts.Threshold {
+		return false
+	}
+	return contentTypeAllowed(b.ResponseWriter.Header().Get("Content-Type"), opts.ContentTypes)
+}
+
+// flushRaw writes the buffered response through unmodified.
+func (b *bufferingWriter) flushRaw() {
+	b.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(b.body.Len()))
+	b.ResponseWriter.WriteHeader(b.status)
+	if b.body.Len() > 0 {
+		_, _ = b.ResponseWriter.Write(b.body.Bytes())
+	}
+}
+
+// flushCompressed writes the buffered response through a gzip or deflate
+// encoder, replacing Content-Length with the compressed size.
+func flushCompressed(w http.ResponseWriter, buf *bufferingWriter, encoding string, level int) {
+	var compressed bytes.Buffer
+
+	var writer io.WriteCloser
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewWriterLevel(&compressed, level)
+		if err != nil {
+			buf.flushRaw()
+			return
+		}
+		writer = gz
+	case "deflate":
+		fl, err := flate.NewWriter(&compressed, level)
+		if err != nil {
+			buf.flushRaw()
+			return
+		}
+		writer = fl
+	default:
+		buf.flushRaw()
+		return
+	}
+
+	if _, err := writer.Write(buf.body.Bytes()); err != nil {
+		buf.flushRaw()
+		return
+	}
+	if err := writer.Close(); err != nil {
+		buf.flushRaw()
+		return
+	}
+
+	w.Header().Set("Content-Encoding", encoding)
+	w.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+	w.WriteHeader(buf.status)
+	_, _ = w.Write(compressed.Bytes())
+}
+
+func contentTypeAllowed(contentType string, allowed []string) bool {
+	if idx := strings.IndexByte(contentType, ';'); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	for _, prefix := range allowed {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodingQuality is one coding parsed out of an Accept-Encoding header,
+// along with its relative "q" weight.
+type encodingQuality struct {
+	coding string
+	q      float64
+}
+
+// negotiateEncoding picks "gzip", "deflate" or "" (no compression) from
+// an Accept-Encoding header, honoring q values and treating
+// "identity;q=0" as an explicit rejection of uncompressed responses
+// (which this middleware does not use as a signal either way, since it
+// only ever emits gzip/deflate or nothing).
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	supported := map[string]bool{"gzip": true, "deflate": true}
+
+	var codings []encodingQuality
+	for _, part := range strings.Split(header, ",") {
+		coding, q := parseCoding(part)
+		if q == 0 {
+			continue
+		}
+		if coding == "*" {
+			codings = append(codings, encodingQuality{coding: "gzip", q: q})
+			continue
+		}
+		if !supported[coding] {
+			continue
+		}
+		codings = append(codings, encodingQuality{coding: coding, q: q})
+	}
+
+	if len(codings) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(codings, func(i, j int) bool { return codings[i].q > codings[j].q })
+
+	return codings[0].coding
+}
+
+func parseCoding(part string) (string, float64) {
+	segments := strings.Split(part, ";")
+	coding := strings.ToLower(strings.TrimSpace(segments[0]))
+
+	q := 1.0
+	for _, param := range segments[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+			q = parsed
+		}
+	}
+
+	return coding, q
+}
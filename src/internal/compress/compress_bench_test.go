@@ -0,0 +1,72 @@
+package compress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type benchProduct struct {
+	ProductID    int    `json:"product_id"`
+	SKU          string `json:"sku"`
+	Manufacturer string `json:"manufacturer"`
+	CategoryID   int    `json:"category_id"`
+	Weight       int    `json:"weight"`
+	SomeOtherID  int    `json:"some_other_id"`
+}
+
+func syntheticProductListBody(b *testing.B) []byte {
+	b.Helper()
+
+	products := make([]benchProduct, 100)
+	for i := range products {
+		products[i] = benchProduct{
+			ProductID:    i + 1,
+			SKU:          "SKU-0000000000-EXAMPLE",
+			Manufacturer: "Example Manufacturing Co.",
+			CategoryID:   (i % 10) + 1,
+			Weight:       500 + i,
+			SomeOtherID:  (i % 5) + 1,
+		}
+	}
+
+	body, err := json.Marshal(products)
+	if err != nil {
+		b.Fatalf("marshal synthetic products: %v", err)
+	}
+	return body
+}
+
+func listHandler(body []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
+
+func BenchmarkListResponse_Uncompressed(b *testing.B) {
+	body := syntheticProductListBody(b)
+	handler := listHandler(body)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
+
+func BenchmarkListResponse_Compressed(b *testing.B) {
+	body := syntheticProductListBody(b)
+	handler := Middleware(OptionsFromEnv())(listHandler(body))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
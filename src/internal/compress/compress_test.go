@@ -0,0 +1,204 @@
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testOptions() Options {
+	return Options{Threshold: 16, Level: gzip.DefaultCompression, ContentTypes: DefaultContentTypes}
+}
+
+func largeJSONHandler() http.Handler {
+	body := []byte(`{"value":"` + strings.Repeat("x", 100) + `"}`)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	})
+}
+
+func TestMiddlewareCompressesWithGzip(t *testing.T) {
+	handler := Middleware(testOptions())(largeJSONHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	if !strings.Contains(string(decoded), `"value"`) {
+		t.Fatalf("decoded body = %q, want it to contain the JSON value field", decoded)
+	}
+}
+
+func TestMiddlewarePrefersDeflateWhenHigherQ(t *testing.T) {
+	handler := Middleware(testOptions())(largeJSONHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate;q=0.8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "deflate" {
+		t.Fatalf("Content-Encoding = %q, want deflate", got)
+	}
+
+	fl := flate.NewReader(rec.Body)
+	decoded, err := io.ReadAll(fl)
+	if err != nil {
+		t.Fatalf("read deflate body: %v", err)
+	}
+	if !strings.Contains(string(decoded), `"value"`) {
+		t.Fatalf("decoded body = %q, want it to contain the JSON value field", decoded)
+	}
+}
+
+func TestMiddlewareSkipsIdentityOnlyRequests(t *testing.T) {
+	handler := Middleware(testOptions())(largeJSONHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "identity;q=0")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want none", got)
+	}
+	if !strings.Contains(rec.Body.String(), `"value"`) {
+		t.Fatalf("body = %q, want the raw JSON passed through", rec.Body.String())
+	}
+}
+
+func TestMiddlewareSkipsSmallResponses(t *testing.T) {
+	handler := Middleware(testOptions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want none for a response below the threshold", got)
+	}
+	if rec.Body.String() != `{}` {
+		t.Fatalf("body = %q, want the raw response unmodified", rec.Body.String())
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "2" {
+		t.Fatalf("Content-Length = %q, want 2", cl)
+	}
+}
+
+func TestMiddlewareSkipsDisallowedContentType(t *testing.T) {
+	handler := Middleware(testOptions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bytes.Repeat([]byte{0xFF}, 100))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want none for a disallowed content type", got)
+	}
+}
+
+func TestMiddlewareSkips204NoContent(t *testing.T) {
+	handler := Middleware(testOptions())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/products/1/details", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want none for a 204 response", got)
+	}
+	if rec.Body.Len() != 0 {
+		t.Fatalf("body length = %d, want 0", rec.Body.Len())
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "0" {
+		t.Fatalf("Content-Length = %q, want 0", cl)
+	}
+}
+
+func TestMiddlewareRewritesContentLengthToCompressedSize(t *testing.T) {
+	handler := Middleware(testOptions())(largeJSONHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	cl := rec.Header().Get("Content-Length")
+	if cl == "" {
+		t.Fatal("Content-Length not set on compressed response")
+	}
+	if cl != strconv.Itoa(rec.Body.Len()) {
+		t.Fatalf("Content-Length = %q, want it to match the compressed body length %d", cl, rec.Body.Len())
+	}
+}
+
+func TestNegotiateEncodingHonorsQValues(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0.3, deflate;q=0.9", "deflate"},
+		{"*;q=0.5", "gzip"},
+		{"br", ""},
+	}
+
+	for _, tc := range tests {
+		if got := negotiateEncoding(tc.header); got != tc.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tc.header, got, tc.want)
+		}
+	}
+}
+
+func TestContentTypeAllowed(t *testing.T) {
+	allowed := []string{"application/json", "text/"}
+
+	if !contentTypeAllowed("application/json; charset=utf-8", allowed) {
+		t.Error("expected application/json with charset to be allowed")
+	}
+	if !contentTypeAllowed("text/plain", allowed) {
+		t.Error("expected text/plain to be allowed")
+	}
+	if contentTypeAllowed("image/png", allowed) {
+		t.Error("expected image/png to be disallowed")
+	}
+}
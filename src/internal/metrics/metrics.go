@@ -0,0 +1,151 @@
+// Package metrics defines the Prometheus collectors used across the
+// service and a registry constructor so tests can exercise isolated
+// instances instead of the global default registry.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics bundles the collectors recorded by the HTTP middleware and the
+// storage layer. A Metrics value is safe for concurrent use.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	RequestsTotal   *prometheus.CounterVec
+	RequestDuration *prometheus.HistogramVec
+	InFlight        prometheus.Gauge
+
+	ProductUpserts  prometheus.Counter
+	ProductGets     prometheus.Counter
+	ProductNotFound prometheus.Counter
+	ProductsInStore prometheus.Gauge
+}
+
+// New creates a Metrics instance registered against a fresh registry.
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		Registry: registry,
+		RequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests processed, labeled by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency distribution of HTTP requests in seconds.",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		}, []string{"method", "route", "status"}),
+		InFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_in_flight_requests",
+			Help: "Number of HTTP requests currently being served.",
+		}),
+		ProductUpserts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "product_upserts_total",
+			Help: "Total number of product upsert operations performed against the store.",
+		}),
+		ProductGets: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "product_gets_total",
+			Help: "Total number of product lookups performed against the store.",
+		}),
+		ProductNotFound: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "product_not_found_total",
+			Help: "Total number of product lookups that found no matching product.",
+		}),
+		ProductsInStore: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "products_in_store",
+			Help: "Current number of products held by the store.",
+		}),
+	}
+
+	registry.MustRegister(
+		m.RequestsTotal,
+		m.RequestDuration,
+		m.InFlight,
+		m.ProductUpserts,
+		m.ProductGets,
+		m.ProductNotFound,
+		m.ProductsInStore,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler serving this registry's metrics in the
+// Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// Middleware returns a chi-compatible middleware that records request
+// counts, latency and in-flight requests. It reads
+// chi.RouteContext(r.Context()) only after the downstream handler returns,
+// once chi's routing has filled in the matched route pattern, so metrics
+// are labeled by that pattern rather than the raw URL, keeping label
+// cardinality bounded when scanners probe unknown paths.
+//
+// Recording happens in a defer so it still fires when a downstream handler
+// panics. middleware.Recoverer, mounted above this one, writes the client's
+// 500 response through the original http.ResponseWriter rather than through
+// ww, so ww.status is never touched by it; this middleware recovers the
+// panic itself first to force ww.status to 500 for recording, then
+// re-panics so Recoverer still owns sending the response.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.InFlight.Inc()
+		defer m.InFlight.Dec()
+
+		start := time.Now()
+		ww := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		defer func() {
+			rvr := recover()
+			if rvr != nil {
+				ww.status = http.StatusInternalServerError
+			}
+
+			route := routePattern(r)
+			status := strconv.Itoa(ww.status)
+
+			m.RequestsTotal.WithLabelValues(r.Method, route, status).Inc()
+			m.RequestDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+
+			if rvr != nil {
+				panic(rvr)
+			}
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// routePattern returns the chi route pattern matched for the request, or
+// "unmatched" when no route context is available (e.g. 404s that never
+// reached a registered route).
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+// statusRecorder captures the status code written by downstream handlers
+// so the middleware can label metrics after the response has been sent.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
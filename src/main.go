@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log"
@@ -15,6 +17,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"hw5/handlers"
+	"hw5/internal/compress"
+	"hw5/internal/metrics"
 	"hw5/storage"
 )
 
@@ -30,7 +34,12 @@ func main() {
 
 	addr := getListenAddr()
 
-	store := storage.NewMemoryStore()
+	promMetrics := metrics.New()
+	store, err := newStore()
+	if err != nil {
+		log.Fatalf("failed to initialize storage backend: %v", err)
+	}
+	store = storage.NewInstrumentedStore(store, promMetrics)
 	handler := handlers.NewHandler(store)
 
 	router := chi.NewRouter()
@@ -38,6 +47,9 @@ func main() {
 	router.Use(middleware.RealIP)
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
+	router.Use(promMetrics.Middleware)
+	router.Use(compress.Middleware(compress.OptionsFromEnv()))
+	router.Use(requestTimeout(requestTimeoutFromEnv()))
 
 	router.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -45,6 +57,8 @@ func main() {
 		_, _ = w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	router.Handle("/metrics", promMetrics.Handler())
+
 	handler.RegisterRoutes(router)
 
 	srv := &http.Server{
@@ -55,11 +69,33 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	reloader, err := configureTLS(srv)
+	if err != nil {
+		log.Fatalf("failed to configure TLS: %v", err)
+	}
+
+	if reloader != nil {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				log.Println("received SIGHUP, reloading TLS certificate")
+				if err := reloader.Reload(); err != nil {
+					log.Printf("failed to reload TLS certificate: %v", err)
+				}
+			}
+		}()
+	}
+
 	// Graceful shutdown handling
 	serverErrors := make(chan error, 1)
 	go func() {
 		log.Printf("Product service listening on %s", addr)
-		serverErrors <- srv.ListenAndServe()
+		if reloader != nil {
+			serverErrors <- srv.ListenAndServeTLS("", "")
+		} else {
+			serverErrors <- srv.ListenAndServe()
+		}
 	}()
 
 	// Wait for interrupt signal or server error
@@ -85,6 +121,110 @@ func main() {
 		}
 		log.Println("server stopped")
 	}
+
+	if err := store.Close(); err != nil {
+		log.Printf("error closing storage backend: %v", err)
+	}
+}
+
+// newStore constructs the storage backend selected by the STORE_BACKEND
+// environment variable (one of "memory", "bolt", "postgres"), defaulting
+// to "memory" when unset.
+func newStore() (storage.Store, error) {
+	switch backend := os.Getenv("STORE_BACKEND"); backend {
+	case "", "memory":
+		return storage.NewMemoryStore(), nil
+	case "bolt":
+		path := os.Getenv("BOLT_PATH")
+		if path == "" {
+			path = "products.db"
+		}
+		return storage.NewBoltStore(path)
+	case "postgres":
+		dsn := os.Getenv("POSTGRES_DSN")
+		if dsn == "" {
+			return nil, fmt.Errorf("POSTGRES_DSN must be set when STORE_BACKEND=postgres")
+		}
+		return storage.NewPostgresStore(context.Background(), dsn)
+	default:
+		return nil, fmt.Errorf("unknown STORE_BACKEND %q", backend)
+	}
+}
+
+// configureTLS wires a TLS listener onto srv when TLS_CERT_FILE and
+// TLS_KEY_FILE are set, returning the certReloader so callers can trigger
+// reloads (e.g. on SIGHUP). It returns a nil reloader when TLS is not
+// configured, in which case the caller should fall back to plaintext.
+// When TLS_CLIENT_CA_FILE is also set, client certificates are required
+// and verified against that CA pool (mutual TLS).
+func configureTLS(srv *http.Server) (*certReloader, error) {
+	certFile := os.Getenv("TLS_CERT_FILE")
+	keyFile := os.Getenv("TLS_KEY_FILE")
+	if certFile == "" || keyFile == "" {
+		return nil, nil
+	}
+
+	reloader, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if caFile := os.Getenv("TLS_CLIENT_CA_FILE"); caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read TLS client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no valid certificates found in %s", caFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	srv.TLSConfig = tlsConfig
+
+	return reloader, nil
+}
+
+// defaultRequestTimeout is used when REQUEST_TIMEOUT is unset or invalid.
+const defaultRequestTimeout = 2 * time.Second
+
+// requestTimeoutFromEnv parses REQUEST_TIMEOUT (a duration string such as
+// "2s" or "500ms") and falls back to defaultRequestTimeout when unset or
+// invalid.
+func requestTimeoutFromEnv() time.Duration {
+	raw := os.Getenv("REQUEST_TIMEOUT")
+	if raw == "" {
+		return defaultRequestTimeout
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return defaultRequestTimeout
+	}
+
+	return timeout
+}
+
+// requestTimeout returns a middleware that derives a per-request context
+// deadline of timeout, so handlers and stores can abort long-running work
+// (e.g. MemoryStore.ListProducts scanning between iterations) promptly.
+func requestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func getListenAddr() string {
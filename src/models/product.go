@@ -1,11 +1,14 @@
 package models
 
+import "encoding/xml"
+
 // Product represents the product model defined in the OpenAPI specification.
 type Product struct {
-	ProductID    int    `json:"product_id"`
-	SKU          string `json:"sku"`
-	Manufacturer string `json:"manufacturer"`
-	CategoryID   int    `json:"category_id"`
-	Weight       int    `json:"weight"`
-	SomeOtherID  int    `json:"some_other_id"`
+	XMLName      xml.Name `json:"-" xml:"product" form:"-"`
+	ProductID    int      `json:"product_id" xml:"product_id" form:"product_id"`
+	SKU          string   `json:"sku" xml:"sku" form:"sku"`
+	Manufacturer string   `json:"manufacturer" xml:"manufacturer" form:"manufacturer"`
+	CategoryID   int      `json:"category_id" xml:"category_id" form:"category_id"`
+	Weight       int      `json:"weight" xml:"weight" form:"weight"`
+	SomeOtherID  int      `json:"some_other_id" xml:"some_other_id" form:"some_other_id"`
 }
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"hw5/models"
+)
+
+// productsBucket is the single bbolt bucket products are stored in, keyed
+// by big-endian uint64 ProductID so iteration order matches ID order.
+var productsBucket = []byte("products")
+
+// BoltStore persists products to a single bbolt file. It implements Store.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database at path and
+// ensures the products bucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(productsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func productKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+// UpsertProduct creates or updates a product by its identifier.
+func (s *BoltStore) UpsertProduct(ctx context.Context, product models.Product) error {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(productsBucket).Put(productKey(product.ProductID), data)
+	})
+}
+
+// GetProduct fetches a product by ID.
+func (s *BoltStore) GetProduct(ctx context.Context, id int) (models.Product, error) {
+	var product models.Product
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(productsBucket).Get(productKey(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &product)
+	})
+
+	return product, err
+}
+
+// DeleteProduct removes a product by ID.
+func (s *BoltStore) DeleteProduct(ctx context.Context, id int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(productsBucket)
+		key := productKey(id)
+		if bucket.Get(key) == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete(key)
+	})
+}
+
+// ListProducts returns a page of products matching filter, sorted by
+// ProductID (the natural iteration order of the big-endian keyed bucket).
+func (s *BoltStore) ListProducts(ctx context.Context, filter Filter, page, limit int) ([]models.Product, int, error) {
+	var matched []models.Product
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(productsBucket).ForEach(func(_, data []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			var product models.Product
+			if err := json.Unmarshal(data, &product); err != nil {
+				return err
+			}
+			if matchesFilter(product, filter) {
+				matched = append(matched, product)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ProductID < matched[j].ProductID })
+
+	total := len(matched)
+	start := (page - 1) * limit
+	if start >= total {
+		return []models.Product{}, total, nil
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return matched[start:end], total, nil
+}
+
+// Close releases the underlying bbolt file handle.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"hw5/internal/metrics"
+	"hw5/models"
+)
+
+// instrumentedStore wraps a Store and records the product-level metrics
+// (product_upserts_total, product_gets_total, product_not_found_total,
+// products_in_store) regardless of which backend is selected.
+type instrumentedStore struct {
+	Store
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedStore wraps store so its operations update m. If m is
+// nil, store is returned unwrapped. The products_in_store gauge is
+// populated immediately so a restart against a persistent backend (Bolt,
+// Postgres) reports the correct count before the first write, rather than
+// reading zero until something triggers a refresh.
+func NewInstrumentedStore(store Store, m *metrics.Metrics) Store {
+	if m == nil {
+		return store
+	}
+	s := &instrumentedStore{Store: store, metrics: m}
+	s.refreshProductsInStore(context.Background())
+	return s
+}
+
+// GetProduct fetches a product by ID, recording the lookup and, on a miss,
+// the not-found count.
+func (s *instrumentedStore) GetProduct(ctx context.Context, id int) (models.Product, error) {
+	product, err := s.Store.GetProduct(ctx, id)
+
+	s.metrics.ProductGets.Inc()
+	if errors.Is(err, ErrNotFound) {
+		s.metrics.ProductNotFound.Inc()
+	}
+
+	return product, err
+}
+
+// UpsertProduct creates or updates a product, recording the upsert and
+// refreshing the products_in_store gauge.
+func (s *instrumentedStore) UpsertProduct(ctx context.Context, product models.Product) error {
+	err := s.Store.UpsertProduct(ctx, product)
+	if err != nil {
+		return err
+	}
+
+	s.metrics.ProductUpserts.Inc()
+	s.refreshProductsInStore(ctx)
+
+	return nil
+}
+
+// DeleteProduct removes a product by ID, refreshing the products_in_store
+// gauge on success.
+func (s *instrumentedStore) DeleteProduct(ctx context.Context, id int) error {
+	err := s.Store.DeleteProduct(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	s.refreshProductsInStore(ctx)
+
+	return nil
+}
+
+// refreshProductsInStore re-derives the current product count from the
+// wrapped store's ListProducts total, rather than tracking it separately,
+// so it stays correct across every backend without each one exposing its
+// own count method.
+func (s *instrumentedStore) refreshProductsInStore(ctx context.Context) {
+	_, total, err := s.Store.ListProducts(ctx, Filter{}, 1, 1)
+	if err != nil {
+		return
+	}
+	s.metrics.ProductsInStore.Set(float64(total))
+}
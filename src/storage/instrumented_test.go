@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"hw5/internal/metrics"
+	"hw5/models"
+)
+
+func TestNewInstrumentedStorePopulatesGaugeAtConstruction(t *testing.T) {
+	store := NewMemoryStore()
+	for _, product := range seededProducts(3) {
+		if err := store.UpsertProduct(context.Background(), product); err != nil {
+			t.Fatalf("UpsertProduct(%d): %v", product.ProductID, err)
+		}
+	}
+
+	m := metrics.New()
+	NewInstrumentedStore(store, m)
+
+	if got := testutil.ToFloat64(m.ProductsInStore); got != 3 {
+		t.Fatalf("products_in_store = %v, want 3", got)
+	}
+}
+
+func seededProducts(n int) []models.Product {
+	products := make([]models.Product, n)
+	for i := range products {
+		products[i] = models.Product{ProductID: i + 1, SKU: "sku", Manufacturer: "m", CategoryID: 1, SomeOtherID: 1}
+	}
+	return products
+}
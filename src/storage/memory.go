@@ -1,13 +1,18 @@
 package storage
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"strings"
 	"sync"
 
 	"hw5/models"
 )
 
-// MemoryStore keeps products in-memory using a concurrency-safe map.
+// MemoryStore keeps products in-memory using a concurrency-safe map. It
+// implements Store and is the default backend used in tests and local
+// development.
 type MemoryStore struct {
 	mu       sync.RWMutex
 	products map[int]models.Product
@@ -23,15 +28,17 @@ func NewMemoryStore() *MemoryStore {
 var ErrNotFound = errors.New("product not found")
 
 // UpsertProduct creates or updates a product by its identifier.
-func (s *MemoryStore) UpsertProduct(product models.Product) {
+func (s *MemoryStore) UpsertProduct(ctx context.Context, product models.Product) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.products[product.ProductID] = product
+
+	return nil
 }
 
 // GetProduct fetches a product by ID.
-func (s *MemoryStore) GetProduct(id int) (models.Product, error) {
+func (s *MemoryStore) GetProduct(ctx context.Context, id int) (models.Product, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -42,3 +49,67 @@ func (s *MemoryStore) GetProduct(id int) (models.Product, error) {
 
 	return product, nil
 }
+
+// DeleteProduct removes a product by ID.
+func (s *MemoryStore) DeleteProduct(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.products[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.products, id)
+
+	return nil
+}
+
+// ListProducts returns a page of products matching filter, sorted by
+// ProductID for stable pagination.
+func (s *MemoryStore) ListProducts(ctx context.Context, filter Filter, page, limit int) ([]models.Product, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]models.Product, 0, len(s.products))
+	for _, product := range s.products {
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
+		if matchesFilter(product, filter) {
+			matched = append(matched, product)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ProductID < matched[j].ProductID })
+
+	total := len(matched)
+	start := (page - 1) * limit
+	if start >= total {
+		return []models.Product{}, total, nil
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return matched[start:end], total, nil
+}
+
+// Close is a no-op for MemoryStore; there are no resources to release.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func matchesFilter(product models.Product, filter Filter) bool {
+	if filter.Manufacturer != "" && product.Manufacturer != filter.Manufacturer {
+		return false
+	}
+	if filter.CategoryID != 0 && product.CategoryID != filter.CategoryID {
+		return false
+	}
+	if filter.SKUPrefix != "" && !strings.HasPrefix(product.SKU, filter.SKUPrefix) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hw5/models"
+)
+
+func seedMemoryStore(t *testing.T, products ...models.Product) *MemoryStore {
+	t.Helper()
+
+	store := NewMemoryStore()
+	for _, product := range products {
+		if err := store.UpsertProduct(context.Background(), product); err != nil {
+			t.Fatalf("UpsertProduct(%d): %v", product.ProductID, err)
+		}
+	}
+	return store
+}
+
+func TestMemoryStoreDeleteProduct(t *testing.T) {
+	store := seedMemoryStore(t, models.Product{ProductID: 1, SKU: "a", Manufacturer: "m", CategoryID: 1, SomeOtherID: 1})
+
+	if err := store.DeleteProduct(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+
+	if _, err := store.GetProduct(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetProduct after delete: expected ErrNotFound, got %v", err)
+	}
+
+	if err := store.DeleteProduct(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("DeleteProduct on missing product: expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStoreListProductsFiltering(t *testing.T) {
+	store := seedMemoryStore(t,
+		models.Product{ProductID: 1, SKU: "ALPHA-1", Manufacturer: "Acme", CategoryID: 1, SomeOtherID: 1},
+		models.Product{ProductID: 2, SKU: "BETA-1", Manufacturer: "Acme", CategoryID: 2, SomeOtherID: 1},
+		models.Product{ProductID: 3, SKU: "ALPHA-2", Manufacturer: "Globex", CategoryID: 1, SomeOtherID: 1},
+	)
+
+	cases := []struct {
+		name   string
+		filter Filter
+		want   []int
+	}{
+		{"no filter", Filter{}, []int{1, 2, 3}},
+		{"manufacturer", Filter{Manufacturer: "Acme"}, []int{1, 2}},
+		{"category", Filter{CategoryID: 1}, []int{1, 3}},
+		{"sku prefix", Filter{SKUPrefix: "ALPHA"}, []int{1, 3}},
+		{"manufacturer and category", Filter{Manufacturer: "Acme", CategoryID: 2}, []int{2}},
+		{"no match", Filter{Manufacturer: "Nobody"}, []int{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, total, err := store.ListProducts(context.Background(), tc.filter, 1, 50)
+			if err != nil {
+				t.Fatalf("ListProducts: %v", err)
+			}
+			if total != len(tc.want) {
+				t.Fatalf("total = %d, want %d", total, len(tc.want))
+			}
+			if len(items) != len(tc.want) {
+				t.Fatalf("len(items) = %d, want %d", len(items), len(tc.want))
+			}
+			for i, id := range tc.want {
+				if items[i].ProductID != id {
+					t.Errorf("items[%d].ProductID = %d, want %d", i, items[i].ProductID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreListProductsPagination(t *testing.T) {
+	products := make([]models.Product, 5)
+	for i := range products {
+		products[i] = models.Product{ProductID: i + 1, SKU: "sku", Manufacturer: "m", CategoryID: 1, SomeOtherID: 1}
+	}
+	store := seedMemoryStore(t, products...)
+
+	cases := []struct {
+		name        string
+		page, limit int
+		want        []int
+		wantTotal   int
+	}{
+		{"first page", 1, 2, []int{1, 2}, 5},
+		{"second page", 2, 2, []int{3, 4}, 5},
+		{"last partial page", 3, 2, []int{5}, 5},
+		{"page past the end", 4, 2, []int{}, 5},
+		{"limit covers all", 1, 10, []int{1, 2, 3, 4, 5}, 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, total, err := store.ListProducts(context.Background(), Filter{}, tc.page, tc.limit)
+			if err != nil {
+				t.Fatalf("ListProducts: %v", err)
+			}
+			if total != tc.wantTotal {
+				t.Fatalf("total = %d, want %d", total, tc.wantTotal)
+			}
+			if len(items) != len(tc.want) {
+				t.Fatalf("len(items) = %d, want %d", len(items), len(tc.want))
+			}
+			for i, id := range tc.want {
+				if items[i].ProductID != id {
+					t.Errorf("items[%d].ProductID = %d, want %d", i, items[i].ProductID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreListProductsRespectsCancelledContext(t *testing.T) {
+	store := seedMemoryStore(t, models.Product{ProductID: 1, SKU: "a", Manufacturer: "m", CategoryID: 1, SomeOtherID: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := store.ListProducts(ctx, Filter{}, 1, 50); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ListProducts with cancelled context: expected context.Canceled, got %v", err)
+	}
+}
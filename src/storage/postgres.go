@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"hw5/models"
+)
+
+// schemaBootstrap creates the products table on first use. Production
+// deployments are expected to own their own migration tooling; this
+// bootstrap only exists to make the Postgres backend usable out of the box.
+const schemaBootstrap = `
+CREATE TABLE IF NOT EXISTS products (
+	product_id     BIGINT PRIMARY KEY,
+	sku            TEXT NOT NULL,
+	manufacturer   TEXT NOT NULL,
+	category_id    BIGINT NOT NULL,
+	weight         BIGINT NOT NULL,
+	some_other_id  BIGINT NOT NULL
+);
+`
+
+// PostgresStore persists products to a Postgres database via a pgx
+// connection pool. It implements Store.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore connects to dsn and bootstraps the products schema.
+func NewPostgresStore(ctx context.Context, dsn string) (*PostgresStore, error) {
+	pool, err := pgxpool.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+
+	if _, err := pool.Exec(ctx, schemaBootstrap); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("bootstrap schema: %w", err)
+	}
+
+	return &PostgresStore{pool: pool}, nil
+}
+
+// UpsertProduct creates or updates a product by its identifier.
+func (s *PostgresStore) UpsertProduct(ctx context.Context, product models.Product) error {
+	const query = `
+		INSERT INTO products (product_id, sku, manufacturer, category_id, weight, some_other_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (product_id) DO UPDATE SET
+			sku = EXCLUDED.sku,
+			manufacturer = EXCLUDED.manufacturer,
+			category_id = EXCLUDED.category_id,
+			weight = EXCLUDED.weight,
+			some_other_id = EXCLUDED.some_other_id
+	`
+
+	_, err := s.pool.Exec(ctx, query,
+		product.ProductID, product.SKU, product.Manufacturer,
+		product.CategoryID, product.Weight, product.SomeOtherID)
+	return err
+}
+
+// GetProduct fetches a product by ID.
+func (s *PostgresStore) GetProduct(ctx context.Context, id int) (models.Product, error) {
+	const query = `
+		SELECT product_id, sku, manufacturer, category_id, weight, some_other_id
+		FROM products WHERE product_id = $1
+	`
+
+	var product models.Product
+	row := s.pool.QueryRow(ctx, query, id)
+	err := row.Scan(&product.ProductID, &product.SKU, &product.Manufacturer,
+		&product.CategoryID, &product.Weight, &product.SomeOtherID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Product{}, ErrNotFound
+		}
+		return models.Product{}, err
+	}
+
+	return product, nil
+}
+
+// DeleteProduct removes a product by ID.
+func (s *PostgresStore) DeleteProduct(ctx context.Context, id int) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM products WHERE product_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListProducts returns a page of products matching filter, along with the
+// total number of matching rows.
+func (s *PostgresStore) ListProducts(ctx context.Context, filter Filter, page, limit int) ([]models.Product, int, error) {
+	where, args := filter.whereClause()
+
+	countQuery := "SELECT COUNT(*) FROM products " + where
+	var total int
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	listQuery := fmt.Sprintf(
+		`SELECT product_id, sku, manufacturer, category_id, weight, some_other_id
+		 FROM products %s ORDER BY product_id LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2)
+	args = append(args, limit, (page-1)*limit)
+
+	rows, err := s.pool.Query(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := make([]models.Product, 0, limit)
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.ProductID, &product.SKU, &product.Manufacturer,
+			&product.CategoryID, &product.Weight, &product.SomeOtherID); err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+
+	return products, total, rows.Err()
+}
+
+// Close releases the underlying connection pool.
+func (s *PostgresStore) Close() error {
+	s.pool.Close()
+	return nil
+}
+
+// whereClause renders filter as a SQL WHERE clause and its positional args.
+func (f Filter) whereClause() (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if f.Manufacturer != "" {
+		args = append(args, f.Manufacturer)
+		clauses = append(clauses, fmt.Sprintf("manufacturer = $%d", len(args)))
+	}
+	if f.CategoryID != 0 {
+		args = append(args, f.CategoryID)
+		clauses = append(clauses, fmt.Sprintf("category_id = $%d", len(args)))
+	}
+	if f.SKUPrefix != "" {
+		args = append(args, f.SKUPrefix+"%")
+		clauses = append(clauses, fmt.Sprintf("sku LIKE $%d", len(args)))
+	}
+
+	if len(clauses) == 0 {
+		return "", args
+	}
+
+	where := "WHERE "
+	for i, clause := range clauses {
+		if i > 0 {
+			where += " AND "
+		}
+		where += clause
+	}
+	return where, args
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+
+	"hw5/models"
+)
+
+// Filter narrows the results returned by ListProducts. Zero-valued fields
+// are treated as "no constraint" for that field.
+type Filter struct {
+	Manufacturer string
+	CategoryID   int
+	SKUPrefix    string
+}
+
+// Store is the persistence contract shared by every backend (in-memory,
+// BoltDB, Postgres, ...). All methods accept a context so callers can
+// propagate request deadlines and cancellation through to the backend.
+type Store interface {
+	// GetProduct fetches a product by ID, returning ErrNotFound if absent.
+	GetProduct(ctx context.Context, id int) (models.Product, error)
+
+	// UpsertProduct creates or updates a product by its identifier.
+	UpsertProduct(ctx context.Context, product models.Product) error
+
+	// DeleteProduct removes a product by ID, returning ErrNotFound if absent.
+	DeleteProduct(ctx context.Context, id int) error
+
+	// ListProducts returns a page of products matching filter, along with
+	// the total number of matching products across all pages.
+	ListProducts(ctx context.Context, filter Filter, page, limit int) ([]models.Product, int, error)
+
+	// Close releases any resources held by the backend (file handles,
+	// connection pools, ...). It is safe to call Close more than once.
+	Close() error
+}